@@ -0,0 +1,304 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/afero"
+)
+
+// auditMaxBytesDefault is how large audit.log is allowed to grow before
+// it's rotated, unless overridden by DONTRM_AUDIT_MAX_BYTES.
+const auditMaxBytesDefault = 10 * 1024 * 1024
+
+// auditHashMaxBytes caps which removed files get a sha256 recorded; hashing
+// huge files on every invocation would defeat the point of an audit log.
+const auditHashMaxBytes = 1 * 1024 * 1024
+
+// auditMaxBackups bounds how many rotated audit.log.N files accumulate.
+const auditMaxBackups = 5
+
+// AuditEntry is one append-only record of a dontrm invocation.
+type AuditEntry struct {
+	Timestamp      time.Time `json:"timestamp"`
+	PID            int       `json:"pid"`
+	UID            int       `json:"uid"`
+	Cwd            string    `json:"cwd"`
+	Argv           []string  `json:"argv"`
+	ResolvedPaths  []string  `json:"resolved_paths"`
+	GlobExpansions []string  `json:"glob_expansions,omitempty"`
+	Classification string    `json:"classification"`
+	// Executed is true only when this invocation actually removed or
+	// trashed something; false for blocked, cancelled-risky, and dry-run
+	// invocations, which are otherwise indistinguishable from a real
+	// removal in the classification/exit_code fields alone.
+	Executed bool              `json:"executed"`
+	ExitCode int               `json:"exit_code"`
+	SHA256   map[string]string `json:"sha256,omitempty"`
+}
+
+// auditLogPath resolves the audit log location under $XDG_STATE_HOME,
+// falling back to ~/.local/state/dontrm/audit.log.
+func auditLogPath() (string, error) {
+	if xdg := os.Getenv("XDG_STATE_HOME"); xdg != "" {
+		return filepath.Join(xdg, "dontrm", "audit.log"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "state", "dontrm", "audit.log"), nil
+}
+
+// auditMaxBytes returns the configured rotation threshold, honoring
+// DONTRM_AUDIT_MAX_BYTES when set to a positive integer.
+func auditMaxBytes() int64 {
+	if raw := os.Getenv("DONTRM_AUDIT_MAX_BYTES"); raw != "" {
+		if n, err := strconv.ParseInt(raw, 10, 64); err == nil && n > 0 {
+			return n
+		}
+	}
+	return auditMaxBytesDefault
+}
+
+// hashSmallFiles returns the sha256 of each path in paths that exists,
+// isn't a directory, and is no larger than auditHashMaxBytes.
+func hashSmallFiles(fs FS, paths []string) map[string]string {
+	var hashes map[string]string
+	for _, path := range paths {
+		info, err := fs.Stat(path)
+		if err != nil || info.IsDir() || info.Size() > auditHashMaxBytes {
+			continue
+		}
+
+		f, err := fs.Open(path)
+		if err != nil {
+			continue
+		}
+		h := sha256.New()
+		_, err = io.Copy(h, f)
+		_ = f.Close() //nolint:errcheck // best-effort close after hashing
+
+		if err != nil {
+			continue
+		}
+		if hashes == nil {
+			hashes = make(map[string]string)
+		}
+		hashes[path] = hex.EncodeToString(h.Sum(nil))
+	}
+	return hashes
+}
+
+// expandGlobExpansions runs each glob operand in paths through echoGlob and
+// returns the combined, sorted set of matches, so the audit log records what
+// a pattern like /var/log/*.log actually expanded to at removal time.
+func expandGlobExpansions(fs FS, paths []string) []string {
+	var expansions []string
+	for _, p := range paths {
+		if !isGlob(p) {
+			continue
+		}
+		matches, err := echoGlob(p, fs)
+		if err != nil {
+			continue
+		}
+		expansions = append(expansions, matches...)
+	}
+	sort.Strings(expansions)
+	return expansions
+}
+
+// recordAudit appends one entry to the audit log, rotating it first if it's
+// grown past the configured size. A rotation failure is reported on stderr;
+// any other audit failure is silently ignored. Neither stops the user's
+// actual operation.
+func recordAudit(fs FS, args []string, classification Classification, exitCode int, hashes map[string]string, executed bool) {
+	path, err := auditLogPath()
+	if err != nil {
+		return
+	}
+
+	if err := rotateAuditLog(fs, path); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "dontrm: audit rotation failed:", err)
+	}
+
+	cwd, _ := os.Getwd()
+	resolved := make([]string, 0, len(classification.Paths))
+	for _, p := range classification.Paths {
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			abs = p
+		}
+		resolved = append(resolved, abs)
+	}
+
+	entry := AuditEntry{
+		Timestamp:      time.Now(),
+		PID:            os.Getpid(),
+		UID:            os.Getuid(),
+		Cwd:            cwd,
+		Argv:           args,
+		ResolvedPaths:  resolved,
+		GlobExpansions: expandGlobExpansions(fs, classification.Paths),
+		Classification: classification.Risk.String(),
+		Executed:       executed,
+		ExitCode:       exitCode,
+		SHA256:         hashes,
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	if err := fs.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return
+	}
+
+	f, err := fs.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return
+	}
+	defer func() { _ = f.Close() }() //nolint:errcheck // best-effort close after append
+
+	_, _ = f.Write(append(data, '\n'))
+}
+
+// rotateAuditLog renames path to path.1 (bumping existing path.N to
+// path.N+1, dropping anything past auditMaxBackups) when path has grown
+// past auditMaxBytes.
+func rotateAuditLog(fs FS, path string) error {
+	info, err := fs.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if info.Size() < auditMaxBytes() {
+		return nil
+	}
+
+	oldest := fmt.Sprintf("%s.%d", path, auditMaxBackups)
+	if exists, _ := afero.Exists(fs, oldest); exists {
+		if err := fs.Remove(oldest); err != nil {
+			return err
+		}
+	}
+	for i := auditMaxBackups - 1; i >= 1; i-- {
+		src := fmt.Sprintf("%s.%d", path, i)
+		if exists, _ := afero.Exists(fs, src); !exists {
+			continue
+		}
+		if err := fs.Rename(src, fmt.Sprintf("%s.%d", path, i+1)); err != nil {
+			return err
+		}
+	}
+	return fs.Rename(path, path+".1")
+}
+
+// auditEntries reads every entry from the audit log, in file order.
+func auditEntries(fs FS) ([]AuditEntry, error) {
+	path, err := auditLogPath()
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := fs.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer func() { _ = f.Close() }() //nolint:errcheck // best-effort close after read
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var entry AuditEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// runAuditCommand implements the `dontrm audit` subcommands.
+func runAuditCommand(args []string, fs FS, stdout, stderr *os.File) int {
+	if len(args) == 0 {
+		_, _ = fmt.Fprintln(stderr, "usage: dontrm audit [tail|grep <pattern>]")
+		return 1
+	}
+
+	entries, err := auditEntries(fs)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err.Error())
+		return 1
+	}
+
+	switch args[0] {
+	case "tail":
+		n := 10
+		if len(args) > 1 {
+			if parsed, err := strconv.Atoi(args[1]); err == nil && parsed > 0 {
+				n = parsed
+			}
+		}
+		if n > len(entries) {
+			n = len(entries)
+		}
+		renderAuditEntries(stdout, entries[len(entries)-n:])
+		return 0
+	case "grep":
+		if len(args) < 2 {
+			_, _ = fmt.Fprintln(stderr, "usage: dontrm audit grep <pattern>")
+			return 1
+		}
+		pattern := args[1]
+		var matched []AuditEntry
+		for _, e := range entries {
+			if strings.Contains(strings.Join(e.Argv, " "), pattern) || strings.Contains(e.Cwd, pattern) {
+				matched = append(matched, e)
+			}
+		}
+		renderAuditEntries(stdout, matched)
+		return 0
+	default:
+		_, _ = fmt.Fprintf(stderr, "unknown audit subcommand: %s\n", args[0])
+		return 1
+	}
+}
+
+// renderAuditEntries renders audit entries one per line, using lipgloss to
+// highlight a non-zero exit code.
+func renderAuditEntries(stdout *os.File, entries []AuditEntry) {
+	failed := lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	for _, e := range entries {
+		line := fmt.Sprintf("%s pid=%d cwd=%s argv=%q classification=%s executed=%t exit=%d",
+			e.Timestamp.Format(time.RFC3339), e.PID, e.Cwd, e.Argv, e.Classification, e.Executed, e.ExitCode)
+		if e.ExitCode != 0 {
+			line = failed.Render(line)
+		}
+		_, _ = fmt.Fprintln(stdout, line)
+	}
+}
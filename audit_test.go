@@ -0,0 +1,227 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestAuditLogPathRespectsXDGStateHome(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", "/xdg-state")
+
+	path, err := auditLogPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "/xdg-state/dontrm/audit.log" {
+		t.Errorf("expected /xdg-state/dontrm/audit.log, got %q", path)
+	}
+}
+
+func TestHashSmallFilesSkipsDirsAndMissing(t *testing.T) {
+	fs := testFS()
+	if err := fs.MkdirAll("/data/dir", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFile(fs, "/data/file.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	hashes := hashSmallFiles(fs, []string{"/data/dir", "/data/file.txt", "/data/missing.txt"})
+	if len(hashes) != 1 {
+		t.Fatalf("expected exactly one hash, got %v", hashes)
+	}
+	if _, ok := hashes["/data/file.txt"]; !ok {
+		t.Errorf("expected a hash for /data/file.txt, got %v", hashes)
+	}
+}
+
+func TestExpandGlobExpansionsOnlyExpandsGlobOperands(t *testing.T) {
+	fs := testFS()
+	if err := fs.MkdirAll("/var/log", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"/var/log/a.log", "/var/log/b.log"} {
+		if err := writeFile(fs, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expansions := expandGlobExpansions(fs, []string{"/var/log/*.log", "/tmp/plain.txt"})
+	if len(expansions) != 2 {
+		t.Fatalf("expected 2 glob expansions, got %v", expansions)
+	}
+}
+
+func TestRecordAuditPopulatesGlobExpansions(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", "/xdg-state")
+	fs := testFS()
+	if err := fs.MkdirAll("/var/log", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFile(fs, "/var/log/a.log"); err != nil {
+		t.Fatal(err)
+	}
+
+	classification := Classification{Risk: RiskSafe, Paths: []string{"/var/log/*.log"}}
+	recordAudit(fs, []string{"/var/log/*.log"}, classification, 0, nil, true)
+
+	entries, err := auditEntries(fs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 1 || len(entries[0].GlobExpansions) != 1 || entries[0].GlobExpansions[0] != "/var/log/a.log" {
+		t.Fatalf("expected glob_expansions to contain /var/log/a.log, got %+v", entries)
+	}
+}
+
+func TestRecordAndReadAuditEntries(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", "/xdg-state")
+	fs := testFS()
+
+	classification := Classification{Risk: RiskSafe, Paths: []string{"/tmp/a.txt"}}
+	recordAudit(fs, []string{"/tmp/a.txt"}, classification, 0, nil, true)
+
+	entries, err := auditEntries(fs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected one entry, got %d", len(entries))
+	}
+	if entries[0].Classification != "safe" || entries[0].ExitCode != 0 || !entries[0].Executed {
+		t.Errorf("unexpected entry: %+v", entries[0])
+	}
+}
+
+// TestRunRecordsExecutedOnlyForRealDeletes pins that a dry run and a real
+// delete, which both classify as safe with exit code 0, are nonetheless
+// distinguishable in the audit log: only the real delete sets Executed.
+func TestRunRecordsExecutedOnlyForRealDeletes(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", "/xdg-state")
+	fs := testFS()
+	if err := writeFile(fs, "/tmp/file1.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFile(fs, "/tmp/file2.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpStdin, err := os.CreateTemp("", "stdin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(tmpStdin.Name()) }() //nolint:errcheck // cleanup in tests
+	defer func() { _ = tmpStdin.Close() }()           //nolint:errcheck // cleanup in tests
+
+	tmpStdout, err := os.CreateTemp("", "stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(tmpStdout.Name()) }() //nolint:errcheck // cleanup in tests
+	defer func() { _ = tmpStdout.Close() }()           //nolint:errcheck // cleanup in tests
+
+	tmpStderr, err := os.CreateTemp("", "stderr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(tmpStderr.Name()) }() //nolint:errcheck // cleanup in tests
+	defer func() { _ = tmpStderr.Close() }()           //nolint:errcheck // cleanup in tests
+
+	fakeExec := &FakeExecutor{}
+
+	t.Setenv("DRY_RUN", "")
+	if code := run([]string{"/tmp/file1.txt"}, tmpStdin, tmpStdout, tmpStderr, fs, fakeExec); code != 0 {
+		t.Fatalf("expected exit 0 for real delete, got %d", code)
+	}
+
+	t.Setenv("DRY_RUN", "1")
+	if code := run([]string{"/tmp/file2.txt"}, tmpStdin, tmpStdout, tmpStderr, fs, fakeExec); code != 0 {
+		t.Fatalf("expected exit 0 for dry run, got %d", code)
+	}
+
+	entries, err := auditEntries(fs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 audit entries, got %d", len(entries))
+	}
+	if !entries[0].Executed {
+		t.Errorf("expected the real delete to be recorded as executed, got %+v", entries[0])
+	}
+	if entries[1].Executed {
+		t.Errorf("expected the dry run to be recorded as not executed, got %+v", entries[1])
+	}
+}
+
+func TestRotateAuditLogRotatesPastThreshold(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", "/xdg-state")
+	t.Setenv("DONTRM_AUDIT_MAX_BYTES", "10")
+	fs := testFS()
+
+	classification := Classification{Risk: RiskSafe, Paths: []string{"/tmp/a.txt"}}
+	recordAudit(fs, []string{"/tmp/a.txt"}, classification, 0, nil, true)
+	recordAudit(fs, []string{"/tmp/a.txt"}, classification, 0, nil, true)
+
+	path, err := auditLogPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exists, _ := fs.Open(path + ".1"); exists == nil {
+		t.Error("expected audit.log.1 to exist after exceeding DONTRM_AUDIT_MAX_BYTES")
+	}
+}
+
+func TestRunAuditCommandUnknownSubcommand(t *testing.T) {
+	fs := testFS()
+	tmp, err := os.CreateTemp("", "audit-out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }() //nolint:errcheck // cleanup in tests
+	defer func() { _ = tmp.Close() }()           //nolint:errcheck // cleanup in tests
+
+	if code := runAuditCommand([]string{"bogus"}, fs, tmp, tmp); code != 1 {
+		t.Errorf("expected exit code 1 for an unknown subcommand, got %d", code)
+	}
+}
+
+// TestRunAuditCommandTailAndGrep exercises the `audit tail`/`audit grep`
+// dispatch end to end against entries written by recordAudit.
+func TestRunAuditCommandTailAndGrep(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", "/xdg-state")
+	fs := testFS()
+
+	recordAudit(fs, []string{"/tmp/a.txt"}, Classification{Risk: RiskSafe, Paths: []string{"/tmp/a.txt"}}, 0, nil, true)
+	recordAudit(fs, []string{"/var/log/b.txt"}, Classification{Risk: RiskSafe, Paths: []string{"/var/log/b.txt"}}, 0, nil, true)
+
+	tmpOut, err := os.CreateTemp("", "audit-out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(tmpOut.Name()) }() //nolint:errcheck // cleanup in tests
+	defer func() { _ = tmpOut.Close() }()           //nolint:errcheck // cleanup in tests
+
+	if code := runAuditCommand([]string{"tail", "1"}, fs, tmpOut, tmpOut); code != 0 {
+		t.Fatalf("expected exit 0 for audit tail, got %d", code)
+	}
+	_, _ = tmpOut.Seek(0, 0) //nolint:errcheck // test helper
+	output := make([]byte, 4096)
+	n, _ := tmpOut.Read(output) //nolint:errcheck // test helper
+	if !strings.Contains(string(output[:n]), "/var/log/b.txt") || strings.Contains(string(output[:n]), "/tmp/a.txt") {
+		t.Errorf("expected audit tail 1 to show only the most recent entry, got %q", output[:n])
+	}
+
+	if err := tmpOut.Truncate(0); err != nil {
+		t.Fatal(err)
+	}
+	if code := runAuditCommand([]string{"grep", "a.txt"}, fs, tmpOut, tmpOut); code != 0 {
+		t.Fatalf("expected exit 0 for audit grep, got %d", code)
+	}
+	_, _ = tmpOut.Seek(0, 0)   //nolint:errcheck // test helper
+	n, _ = tmpOut.Read(output) //nolint:errcheck // test helper
+	if !strings.Contains(string(output[:n]), "/tmp/a.txt") || strings.Contains(string(output[:n]), "/var/log/b.txt") {
+		t.Errorf("expected audit grep a.txt to match only the /tmp/a.txt entry, got %q", output[:n])
+	}
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"gopkg.in/yaml.v3"
+)
+
+// systemConfigPath is the system-wide default configuration file. It is
+// OS-specific; see path_unix.go / path_windows.go.
+
+// Rule is a single protection or allow rule merged into the active Ruleset.
+// Source records the config file the rule came from, or "builtin" for the
+// hard-coded systemPaths entries.
+type Rule struct {
+	Path                string
+	Pattern             string
+	RecursiveOnly       bool
+	RequireConfirmation bool
+	Source              string
+}
+
+// Ruleset is the in-memory, merged set of rules consulted by checkArgs.
+type Ruleset struct {
+	ProtectedPaths []Rule
+	ProtectedGlobs []Rule
+	AllowPaths     map[string]bool
+}
+
+// pathRule is the YAML shape of an entry under protected_paths.
+type pathRule struct {
+	Path                string `yaml:"path"`
+	RecursiveOnly       bool   `yaml:"recursive_only"`
+	RequireConfirmation bool   `yaml:"require_confirmation"`
+}
+
+// globRule is the YAML shape of an entry under protected_globs.
+type globRule struct {
+	Pattern             string `yaml:"pattern"`
+	RequireConfirmation bool   `yaml:"require_confirmation"`
+}
+
+// fileConfig is the top-level YAML document loaded from a config source.
+type fileConfig struct {
+	ProtectedPaths []pathRule `yaml:"protected_paths"`
+	ProtectedGlobs []globRule `yaml:"protected_globs"`
+	AllowPaths     []string   `yaml:"allow_paths"`
+}
+
+// validate rejects rules that are missing the field they're keyed on.
+func (fc *fileConfig) validate(source string) error {
+	for i, r := range fc.ProtectedPaths {
+		if strings.TrimSpace(r.Path) == "" {
+			return fmt.Errorf("%s: protected_paths[%d]: path must not be empty", source, i)
+		}
+	}
+	for i, r := range fc.ProtectedGlobs {
+		if strings.TrimSpace(r.Pattern) == "" {
+			return fmt.Errorf("%s: protected_globs[%d]: pattern must not be empty", source, i)
+		}
+	}
+	for i, p := range fc.AllowPaths {
+		if strings.TrimSpace(p) == "" {
+			return fmt.Errorf("%s: allow_paths[%d]: path must not be empty", source, i)
+		}
+	}
+	return nil
+}
+
+// defaultRuleset seeds a Ruleset from the built-in systemPaths map.
+func defaultRuleset() *Ruleset {
+	rs := &Ruleset{AllowPaths: map[string]bool{}}
+	for _, p := range systemPaths {
+		rs.ProtectedPaths = append(rs.ProtectedPaths, Rule{Path: canonicalizePath(p), Source: "builtin"})
+	}
+	return rs
+}
+
+// merge folds a loaded fileConfig into rs, tagging each rule with source.
+func (rs *Ruleset) merge(fc *fileConfig, source string) {
+	for _, r := range fc.ProtectedPaths {
+		rs.ProtectedPaths = append(rs.ProtectedPaths, Rule{
+			Path:                canonicalizePath(r.Path),
+			RecursiveOnly:       r.RecursiveOnly,
+			RequireConfirmation: r.RequireConfirmation,
+			Source:              source,
+		})
+	}
+	for _, r := range fc.ProtectedGlobs {
+		rs.ProtectedGlobs = append(rs.ProtectedGlobs, Rule{
+			Pattern:             r.Pattern,
+			RequireConfirmation: r.RequireConfirmation,
+			Source:              source,
+		})
+	}
+	for _, p := range fc.AllowPaths {
+		rs.AllowPaths[canonicalizePath(p)] = true
+	}
+}
+
+// userConfigPath resolves the per-user config location, honoring XDG_CONFIG_HOME.
+func userConfigPath() (string, error) {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "dontrm", "config.yaml"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "dontrm", "config.yaml"), nil
+}
+
+// configSources lists config files in load order: system default first, then
+// the user override, so user rules extend rather than replace system ones.
+func configSources() ([]string, error) {
+	sources := []string{systemConfigPath}
+	user, err := userConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	return append(sources, user), nil
+}
+
+// loadFileConfig reads and validates a single YAML config source.
+func loadFileConfig(path string) (*fileConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fc fileConfig
+	if err := yaml.Unmarshal(data, &fc); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if err := fc.validate(path); err != nil {
+		return nil, err
+	}
+	return &fc, nil
+}
+
+// loadRuleset builds the effective Ruleset: the built-in systemPaths plus
+// every config source that exists, merged in order.
+func loadRuleset() (*Ruleset, error) {
+	rs := defaultRuleset()
+
+	sources, err := configSources()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range sources {
+		fc, err := loadFileConfig(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		rs.merge(fc, path)
+	}
+
+	return rs, nil
+}
+
+// runConfigCommand implements the `dontrm config` subcommands.
+func runConfigCommand(args []string, rs *Ruleset, stdout, stderr *os.File) int {
+	if len(args) == 0 {
+		_, _ = fmt.Fprintln(stderr, "usage: dontrm config [validate|list]")
+		return 1
+	}
+
+	switch args[0] {
+	case "validate":
+		_, _ = fmt.Fprintln(stdout, lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("2")).Render("✔ config is valid"))
+		return 0
+	case "list":
+		_, _ = fmt.Fprint(stdout, renderRuleset(rs))
+		return 0
+	default:
+		_, _ = fmt.Fprintf(stderr, "unknown config subcommand: %s\n", args[0])
+		return 1
+	}
+}
+
+// renderRuleset renders the effective ruleset, one rule per line, using
+// lipgloss for the section headers. Protected paths and globs are listed with
+// their source file; allowed paths are listed by path only, without a source.
+func renderRuleset(rs *Ruleset) string {
+	header := lipgloss.NewStyle().Bold(true).Underline(true)
+	var b strings.Builder
+
+	b.WriteString(header.Render("Protected paths") + "\n")
+	paths := append([]Rule(nil), rs.ProtectedPaths...)
+	sort.Slice(paths, func(i, j int) bool { return paths[i].Path < paths[j].Path })
+	for _, r := range paths {
+		fmt.Fprintf(&b, "  %-30s source=%s\n", r.Path, r.Source)
+	}
+
+	if len(rs.ProtectedGlobs) > 0 {
+		b.WriteString(header.Render("Protected globs") + "\n")
+		globs := append([]Rule(nil), rs.ProtectedGlobs...)
+		sort.Slice(globs, func(i, j int) bool { return globs[i].Pattern < globs[j].Pattern })
+		for _, r := range globs {
+			fmt.Fprintf(&b, "  %-30s source=%s\n", r.Pattern, r.Source)
+		}
+	}
+
+	if len(rs.AllowPaths) > 0 {
+		b.WriteString(header.Render("Allowed paths") + "\n")
+		allow := make([]string, 0, len(rs.AllowPaths))
+		for p := range rs.AllowPaths {
+			allow = append(allow, p)
+		}
+		sort.Strings(allow)
+		for _, p := range allow {
+			fmt.Fprintf(&b, "  %-30s\n", p)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileConfigValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		fc          fileConfig
+		expectError bool
+	}{
+		{
+			name:        "empty config",
+			fc:          fileConfig{},
+			expectError: false,
+		},
+		{
+			name: "valid protected path",
+			fc: fileConfig{
+				ProtectedPaths: []pathRule{{Path: "/data"}},
+			},
+			expectError: false,
+		},
+		{
+			name: "blank protected path",
+			fc: fileConfig{
+				ProtectedPaths: []pathRule{{Path: "  "}},
+			},
+			expectError: true,
+		},
+		{
+			name: "blank protected glob",
+			fc: fileConfig{
+				ProtectedGlobs: []globRule{{Pattern: ""}},
+			},
+			expectError: true,
+		},
+		{
+			name: "blank allow path",
+			fc: fileConfig{
+				AllowPaths: []string{""},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.fc.validate("test.yaml")
+			if test.expectError && err == nil {
+				t.Error("expected error but got none")
+			}
+			if !test.expectError && err != nil {
+				t.Errorf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestRulesetMerge(t *testing.T) {
+	rs := &Ruleset{AllowPaths: map[string]bool{}}
+	fc := &fileConfig{
+		ProtectedPaths: []pathRule{{Path: "/data/", RequireConfirmation: true}},
+		ProtectedGlobs: []globRule{{Pattern: "/data/*.bak"}},
+		AllowPaths:     []string{"/data/scratch/"},
+	}
+
+	rs.merge(fc, "/etc/dontrm/config.yaml")
+
+	if len(rs.ProtectedPaths) != 1 || rs.ProtectedPaths[0].Path != "/data" {
+		t.Fatalf("expected cleaned path /data, got %+v", rs.ProtectedPaths)
+	}
+	if rs.ProtectedPaths[0].Source != "/etc/dontrm/config.yaml" {
+		t.Errorf("expected source to be recorded, got %q", rs.ProtectedPaths[0].Source)
+	}
+	if len(rs.ProtectedGlobs) != 1 || rs.ProtectedGlobs[0].Pattern != "/data/*.bak" {
+		t.Fatalf("expected glob rule to be merged, got %+v", rs.ProtectedGlobs)
+	}
+	if !rs.AllowPaths["/data/scratch"] {
+		t.Errorf("expected allow path to be cleaned and merged")
+	}
+}
+
+func TestLoadFileConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	yamlContent := "protected_paths:\n  - path: /data\n    require_confirmation: true\nallow_paths:\n  - /data/scratch\n"
+	if err := os.WriteFile(path, []byte(yamlContent), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fc, err := loadFileConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fc.ProtectedPaths) != 1 || fc.ProtectedPaths[0].Path != "/data" {
+		t.Fatalf("expected one protected path /data, got %+v", fc.ProtectedPaths)
+	}
+	if !fc.ProtectedPaths[0].RequireConfirmation {
+		t.Errorf("expected require_confirmation to be true")
+	}
+}
+
+func TestLoadFileConfigMissing(t *testing.T) {
+	_, err := loadFileConfig("/nonexistent/config.yaml")
+	if !os.IsNotExist(err) {
+		t.Errorf("expected os.IsNotExist error, got %v", err)
+	}
+}
+
+func TestLoadFileConfigInvalidYAML(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte("protected_paths: [this is not valid"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := loadFileConfig(path); err == nil {
+		t.Error("expected parse error but got none")
+	}
+}
+
+func TestAllowPathOverridesProtection(t *testing.T) {
+	rs := defaultRuleset()
+	rs.AllowPaths["/etc"] = true
+
+	if _, match := isTopLevelSystemPath("/etc", rs, testFS(), true); match {
+		t.Error("expected /etc to be allowed after being added to AllowPaths")
+	}
+}
+
+// TestAllowPathOverridesDestructiveActions pins that allow_paths overrides
+// the sysPath/* full-wipe check in evaluatePotentiallyDestructiveActions,
+// not just the isTopLevelSystemPath check above.
+func TestAllowPathOverridesDestructiveActions(t *testing.T) {
+	fs := testFS()
+	if err := writeFile(fs, "/etc/passwd"); err != nil {
+		t.Fatal(err)
+	}
+
+	rs := defaultRuleset()
+	if _, match := evaluatePotentiallyDestructiveActions("/etc/passwd", rs, fs, true); !match {
+		t.Fatal("expected wiping all of /etc to be blocked before allow-listing it")
+	}
+
+	rs.AllowPaths["/etc"] = true
+	if _, match := evaluatePotentiallyDestructiveActions("/etc/passwd", rs, fs, true); match {
+		t.Error("expected /etc to be allowed after being added to AllowPaths")
+	}
+}
+
+// TestRunConfigCommandDispatch exercises the `config` subcommand dispatch
+// (validate/list), not just renderRuleset underneath it.
+func TestRunConfigCommandDispatch(t *testing.T) {
+	rs := defaultRuleset()
+	rs.merge(&fileConfig{ProtectedPaths: []pathRule{{Path: "/data"}}}, "/etc/dontrm/config.yaml")
+
+	tmpOut, err := os.CreateTemp("", "config-out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(tmpOut.Name()) }() //nolint:errcheck // cleanup in tests
+	defer func() { _ = tmpOut.Close() }()           //nolint:errcheck // cleanup in tests
+
+	if code := runConfigCommand([]string{"validate"}, rs, tmpOut, tmpOut); code != 0 {
+		t.Fatalf("expected exit 0 for config validate, got %d", code)
+	}
+
+	if code := runConfigCommand([]string{"list"}, rs, tmpOut, tmpOut); code != 0 {
+		t.Fatalf("expected exit 0 for config list, got %d", code)
+	}
+	_, _ = tmpOut.Seek(0, 0) //nolint:errcheck // test helper
+	output := make([]byte, 4096)
+	n, _ := tmpOut.Read(output) //nolint:errcheck // test helper
+	if !strings.Contains(string(output[:n]), "/data") {
+		t.Errorf("expected config list output to include /data, got %q", output[:n])
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// isInteractive reports whether f is attached to a terminal. Non-TTY
+// invocations (scripts, CI) must hard-fail on risky operations instead of
+// hanging on a prompt nobody can answer.
+func isInteractive(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// confirmModel is the Bubble Tea model backing the risky-deletion prompt.
+// The user must type the last path segment of the final target exactly to
+// confirm, mirroring GitHub's repo-delete UX.
+type confirmModel struct {
+	paths      []string
+	fileCount  int
+	totalBytes int64
+	target     string
+	typed      string
+	done       bool
+	result     bool
+}
+
+func newConfirmModel(paths []string, fileCount int, totalBytes int64) confirmModel {
+	last := paths[len(paths)-1]
+	target := filepath.Base(strings.TrimRight(last, string(filepath.Separator)))
+	return confirmModel{paths: paths, fileCount: fileCount, totalBytes: totalBytes, target: target}
+}
+
+func (m confirmModel) Init() tea.Cmd { return nil }
+
+func (m confirmModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.Type {
+	case tea.KeyEnter:
+		m.done = true
+		m.result = m.typed == m.target
+		return m, tea.Quit
+	case tea.KeyEsc, tea.KeyCtrlC:
+		m.done = true
+		m.result = false
+		return m, tea.Quit
+	case tea.KeyBackspace:
+		if len(m.typed) > 0 {
+			m.typed = m.typed[:len(m.typed)-1]
+		}
+	case tea.KeyRunes:
+		m.typed += string(keyMsg.Runes)
+	}
+
+	return m, nil
+}
+
+func (m confirmModel) View() string {
+	warn := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("3"))
+
+	var b strings.Builder
+	fmt.Fprintln(&b, warn.Render("⚠ risky deletion, confirmation required"))
+	fmt.Fprintln(&b)
+	for _, p := range m.paths {
+		fmt.Fprintf(&b, "  %s\n", p)
+	}
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "%d file(s), %s total\n", m.fileCount, formatSize(m.totalBytes))
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "Type %q and press enter to confirm: %s\n", m.target, m.typed)
+	return b.String()
+}
+
+// formatSize renders n bytes using IEC binary units (KiB, MiB, ...),
+// matching the style already used for riskyByteSizeThreshold in risk.go.
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for next := n / unit; next >= unit; next /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// confirmRisky runs the Bubble Tea confirmation prompt over stdin/stdout
+// and reports whether the user typed the expected target.
+func confirmRisky(paths []string, fileCount int, totalBytes int64, stdin, stdout *os.File) (bool, error) {
+	model := newConfirmModel(paths, fileCount, totalBytes)
+
+	p := tea.NewProgram(model, tea.WithInput(stdin), tea.WithOutput(stdout))
+	final, err := p.Run()
+	if err != nil {
+		return false, err
+	}
+
+	return final.(confirmModel).result, nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestConfirmModelTypedMatchConfirms(t *testing.T) {
+	m := newConfirmModel([]string{"/home/alice/Documents"}, 3, 4096)
+
+	for _, r := range "Documents" {
+		next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = next.(confirmModel)
+	}
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(confirmModel)
+
+	if !m.done || !m.result {
+		t.Errorf("expected a matching typed confirmation to confirm, got done=%v result=%v", m.done, m.result)
+	}
+}
+
+func TestConfirmModelTypedMismatchRefuses(t *testing.T) {
+	m := newConfirmModel([]string{"/home/alice/Documents"}, 3, 4096)
+
+	for _, r := range "nope" {
+		next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = next.(confirmModel)
+	}
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(confirmModel)
+
+	if !m.done || m.result {
+		t.Errorf("expected a mismatched typed confirmation to refuse, got done=%v result=%v", m.done, m.result)
+	}
+}
+
+func TestConfirmModelEscRefuses(t *testing.T) {
+	m := newConfirmModel([]string{"/home/alice/Documents"}, 3, 4096)
+
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = next.(confirmModel)
+
+	if !m.done || m.result {
+		t.Errorf("expected esc to refuse, got done=%v result=%v", m.done, m.result)
+	}
+}
+
+func TestConfirmModelViewShowsCountAndSize(t *testing.T) {
+	m := newConfirmModel([]string{"/home/alice/Documents"}, 3, 4096)
+
+	view := m.View()
+	if !strings.Contains(view, "3 file(s)") || !strings.Contains(view, "4.0 KiB") {
+		t.Errorf("expected view to show file count and total size, got %q", view)
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		name     string
+		bytes    int64
+		expected string
+	}{
+		{"zero bytes", 0, "0 B"},
+		{"under a KiB", 512, "512 B"},
+		{"exactly a KiB", 1024, "1.0 KiB"},
+		{"a few MiB", 5 * 1024 * 1024, "5.0 MiB"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := formatSize(test.bytes); got != test.expected {
+				t.Errorf("formatSize(%d) = %q; want %q", test.bytes, got, test.expected)
+			}
+		})
+	}
+}
+
+func TestConfirmModelBackspace(t *testing.T) {
+	m := newConfirmModel([]string{"/home/alice/Documents"}, 3, 4096)
+
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m = next.(confirmModel)
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m = next.(confirmModel)
+
+	if m.typed != "" {
+		t.Errorf("expected backspace to clear typed input, got %q", m.typed)
+	}
+}
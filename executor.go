@@ -0,0 +1,18 @@
+package main
+
+import "os"
+
+// Executor abstracts the platform delete command so tests can substitute a
+// fake that records invocations instead of touching the real filesystem.
+type Executor interface {
+	Remove(args []string, stdout, stderr *os.File) error
+}
+
+// OsExecutor runs the real platform delete command (see executeRemove in
+// runner_unix.go / runner_windows.go).
+type OsExecutor struct{}
+
+// Remove implements Executor using the real platform delete command.
+func (OsExecutor) Remove(args []string, stdout, stderr *os.File) error {
+	return executeRemove(args, stdout, stderr)
+}
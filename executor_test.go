@@ -0,0 +1,43 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+// FakeExecutor is a test double for Executor that records every invocation
+// instead of touching the real filesystem.
+type FakeExecutor struct {
+	Calls [][]string
+	Err   error
+}
+
+// Remove implements Executor by recording args and returning the configured error.
+func (f *FakeExecutor) Remove(args []string, stdout, stderr *os.File) error {
+	f.Calls = append(f.Calls, append([]string(nil), args...))
+	return f.Err
+}
+
+func TestFakeExecutorRecordsCalls(t *testing.T) {
+	exec := &FakeExecutor{}
+
+	if err := exec.Remove([]string{"-rf", "foo"}, os.Stdout, os.Stderr); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(exec.Calls) != 1 {
+		t.Fatalf("expected 1 recorded call, got %d", len(exec.Calls))
+	}
+	if exec.Calls[0][0] != "-rf" || exec.Calls[0][1] != "foo" {
+		t.Errorf("expected recorded args [-rf foo], got %v", exec.Calls[0])
+	}
+}
+
+func TestFakeExecutorReturnsConfiguredError(t *testing.T) {
+	wantErr := errors.New("boom")
+	exec := &FakeExecutor{Err: wantErr}
+
+	if err := exec.Remove([]string{"foo"}, os.Stdout, os.Stderr); !errors.Is(err, wantErr) {
+		t.Errorf("expected %v, got %v", wantErr, err)
+	}
+}
@@ -0,0 +1,11 @@
+package main
+
+import "github.com/spf13/afero"
+
+// FS is the filesystem abstraction used throughout dontrm. Production code
+// runs against the real disk (osFS); tests inject an afero.NewMemMapFs so
+// destructive paths like /etc only ever exist in memory.
+type FS = afero.Fs
+
+// osFS is the production filesystem.
+var osFS FS = afero.NewOsFs()
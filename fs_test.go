@@ -0,0 +1,41 @@
+package main
+
+import "testing"
+
+func TestEchoGlobAgainstMemMapFs(t *testing.T) {
+	fs := testFS()
+	if err := fs.MkdirAll("/tmp/sub", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"/tmp/a.txt", "/tmp/b.txt"} {
+		if err := writeFile(fs, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	matches, err := echoGlob("/tmp/*.txt", fs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("expected 2 matches, got %v", matches)
+	}
+}
+
+func TestEchoGlobNoMatchesOnEmptyFs(t *testing.T) {
+	matches, err := echoGlob("/etc/*", testFS())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("expected no matches against an empty filesystem, got %v", matches)
+	}
+}
+
+func writeFile(fs FS, name string) error {
+	f, err := fs.Create(name)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
@@ -6,92 +6,168 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"sort"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/afero"
 )
 
-// systemPaths defines a set of known top-level system paths that should be protected.
-var systemPaths = map[string]string{
-	"/":         "/",
-	"/bin":      "/bin",
-	"/boot":     "/boot",
-	"/dev":      "/dev",
-	"/etc":      "/etc",
-	"/home":     "/home",
-	"/lib":      "/lib",
-	"/lib64":    "/lib64",
-	"/media":    "/media",
-	"/mnt":      "/mnt",
-	"/opt":      "/opt",
-	"/proc":     "/proc",
-	"/root":     "/root",
-	"/run":      "/run",
-	"/sbin":     "/sbin",
-	"/srv":      "/srv",
-	"/sys":      "/sys",
-	"/tmp":      "/tmp",
-	"/usr":      "/usr",
-	"/usr/bin":  "/usr/bin",
-	"/usr/sbin": "/usr/sbin",
-	"/var":      "/var",
-}
+// systemPaths defines the set of known top-level system paths that should be
+// protected. Its contents are OS-specific; see syspaths_*.go.
 
 var (
 	// ErrTopLevelPath indicates that a top-level system path was matched.
 	ErrTopLevelPath = errors.New("⛔ Blocked dangerous operation: Cannot delete system directory")
 	// ErrTopLevelChildAllContents indicates that all contents of a top-level directory were matched.
 	ErrTopLevelChildAllContents = errors.New("⛔ Blocked dangerous operation: Cannot delete all contents of system directory")
+	// ErrConfirmationRequired indicates a risky operation was refused because it could not be confirmed.
+	ErrConfirmationRequired = errors.New("⚠ Refused risky operation: confirmation required")
 )
 
 var version = "dev"
 
 func main() {
-	exitCode := run(os.Args[1:], os.Stdout, os.Stderr)
+	exitCode := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr, osFS, OsExecutor{})
 	os.Exit(exitCode)
 }
 
-// run contains the main application logic and returns an exit code.
-// This function is extracted to be testable without side effects.
-func run(args []string, stdout, stderr *os.File) int {
+// run contains the main application logic and returns an exit code. It's a
+// classify -> optionally prompt -> execute pipeline: stdin/stdout/fs/exec
+// are injected so tests can run against an in-memory filesystem, a
+// recording executor, and a non-interactive stdin instead of the real thing.
+func run(args []string, stdin, stdout, stderr *os.File, fs FS, exec Executor) (exitCode int) {
 	// Handle version command
 	if len(args) > 0 && args[0] == "version" {
 		_, _ = fmt.Fprintln(stdout, lipgloss.NewStyle().Bold(true).Render("DON'T rm!"), version)
 		return 0
 	}
 
+	rs, err := loadRuleset()
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err.Error())
+		return 1
+	}
+
+	// Handle config subcommands
+	if len(args) > 0 && args[0] == "config" {
+		return runConfigCommand(args[1:], rs, stdout, stderr)
+	}
+
+	// Handle trash subcommands
+	if len(args) > 0 && args[0] == "trash" {
+		return runTrashCommand(args, fs, stdout, stderr)
+	}
+
+	// Handle audit subcommands
+	if len(args) > 0 && args[0] == "audit" {
+		return runAuditCommand(args[1:], fs, stdout, stderr)
+	}
+
+	toTrash := trashEnabled(args)
+	args = stripTrashFlag(args)
+
 	// Check if dry run mode is enabled
 	dryRun := os.Getenv("DRY_RUN") == "true" || os.Getenv("DRY_RUN") == "1"
 
-	// Validate arguments for safety
-	if err := checkArgs(args); err != nil {
-		_, _ = fmt.Fprintln(stderr, err.Error())
+	// Classify the operation up front so the audit log below always records
+	// what would have happened, and hash removed-file candidates while they
+	// still exist on disk.
+	classification := classify(args, rs, fs)
+	hashes := hashSmallFiles(fs, classification.Paths)
+	executed := false
+	defer func() { recordAudit(fs, args, classification, exitCode, hashes, executed) }()
+
+	if classification.Risk == RiskBlocked {
+		_, _ = fmt.Fprintln(stderr, classification.Err.Error())
 		return 1
 	}
 
+	// Risky operations need confirmation before anything else happens.
+	if classification.Risk == RiskRisky && os.Getenv("DONTRM_ASSUME_YES") != "1" {
+		if !isInteractive(stdin) {
+			_, _ = fmt.Fprintf(stderr, "%s: %s (set DONTRM_ASSUME_YES=1 to proceed non-interactively)\n",
+				ErrConfirmationRequired, strings.Join(classification.Paths, " "))
+			return 1
+		}
+
+		confirmed, err := confirmRisky(classification.Paths, classification.FileCount, classification.TotalBytes, stdin, stdout)
+		if err != nil {
+			_, _ = fmt.Fprintln(stderr, err.Error())
+			return 1
+		}
+		if !confirmed {
+			_, _ = fmt.Fprintf(stderr, "%s: typed confirmation did not match\n", ErrConfirmationRequired)
+			return 1
+		}
+	}
+
 	// In dry run mode, exit successfully without executing rm
 	if dryRun {
 		return 0
 	}
 
-	// Execute the actual rm command
-	cmd := exec.Command("/usr/bin/rm", args...)
-	cmd.Stdout = stdout
-	cmd.Stderr = stderr
-	if err := cmd.Run(); err != nil {
+	if toTrash {
+		for _, path := range filterPaths(args) {
+			if err := moveToTrash(fs, path); err != nil {
+				_, _ = fmt.Fprintln(stderr, err.Error())
+				return 1
+			}
+		}
+		executed = true
+		return 0
+	}
+
+	// Execute the platform's delete command
+	if err := exec.Remove(args, stdout, stderr); err != nil {
 		return 1
 	}
+	executed = true
 
 	return 0
 }
 
-func isTopLevelSystemPath(path string) (string, bool) {
-	cleanPath := filepath.Clean(path)
-	value, ok := systemPaths[cleanPath]
-	return value, ok
+func isTopLevelSystemPath(path string, rs *Ruleset, fs FS, recursive bool) (string, bool) {
+	cleanPath := canonicalizePath(path)
+	if rs.AllowPaths[cleanPath] {
+		return "", false
+	}
+	for _, rule := range rs.ProtectedPaths {
+		// Rules requiring confirmation are handled by the risky tier, not a hard block.
+		if rule.RequireConfirmation {
+			continue
+		}
+		// A recursive_only rule only protects against `rm -r`; a non-recursive
+		// invocation can't remove the directory itself, so let it through.
+		if rule.RecursiveOnly && !recursive {
+			continue
+		}
+		if rule.Path == cleanPath {
+			return rule.Path, true
+		}
+	}
+	return "", false
+}
+
+// isRecursiveInvocation reports whether args requests recursive removal,
+// i.e. rm's -r/-R/--recursive, including combined short flags like -rf.
+func isRecursiveInvocation(args []string) bool {
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if arg == "--recursive" {
+			return true
+		}
+		if strings.HasPrefix(arg, "--") || !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		if strings.ContainsAny(arg, "rR") {
+			return true
+		}
+	}
+	return false
 }
 
 func sanitize(values []string) string {
@@ -105,13 +181,20 @@ func isGlob(path string) bool {
 	return strings.ContainsAny(path, "*?[")
 }
 
-func echoGlob(pattern string) ([]string, error) {
+func echoGlob(pattern string, fs FS) ([]string, error) {
 	if !isGlob(pattern) {
 		return []string{pattern}, nil
 	}
 
-	// Use filepath.Glob to expand the pattern
-	matches, err := filepath.Glob(pattern)
+	// afero.Glob silently treats a malformed bracket expression as "no
+	// matches" instead of erroring like filepath.Glob does, so validate the
+	// pattern ourselves first.
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+
+	// Use afero.Glob to expand the pattern against the injected filesystem
+	matches, err := afero.Glob(fs, pattern)
 	if err != nil {
 		return nil, err
 	}
@@ -119,11 +202,22 @@ func echoGlob(pattern string) ([]string, error) {
 	return matches, nil
 }
 
-func evaluatePotentiallyDestructiveActions(tail string) (string, bool) {
-	for sysPath := range systemPaths {
+func evaluatePotentiallyDestructiveActions(tail string, rs *Ruleset, fs FS, recursive bool) (string, bool) {
+	for _, rule := range rs.ProtectedPaths {
+		// Rules requiring confirmation are handled by the risky tier, not a hard block.
+		if rule.RequireConfirmation {
+			continue
+		}
+		if rule.RecursiveOnly && !recursive {
+			continue
+		}
+		// allow_paths overrides protection consistently with isTopLevelSystemPath.
+		if rs.AllowPaths[rule.Path] {
+			continue
+		}
 		// evaluate sysPath/*
-		evaluated := filepath.Join(sysPath, "*")
-		sysPathTail, err := echoGlob(evaluated)
+		evaluated := filepath.Join(rule.Path, "*")
+		sysPathTail, err := echoGlob(evaluated, fs)
 		if err != nil {
 			log.Println(err)
 			continue
@@ -133,10 +227,27 @@ func evaluatePotentiallyDestructiveActions(tail string) (string, bool) {
 		}
 	}
 
+	for _, rule := range rs.ProtectedGlobs {
+		if rule.RequireConfirmation {
+			continue
+		}
+		globTail, err := echoGlob(rule.Pattern, fs)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		if sanitize(globTail) == tail {
+			return rule.Pattern, true
+		}
+	}
+
 	return "", false
 }
 
-func checkArgs(args []string) error {
+// filterPaths strips CLI flags from args, returning the operands that rm (or
+// the trash mover) would act on. The "--" end-of-options marker itself is
+// kept in the result, and every argument after it is kept as an operand.
+func filterPaths(args []string) []string {
 	tail := make([]string, 0, len(args))
 	stopParsingOptions := false
 	for _, arg := range args {
@@ -149,13 +260,21 @@ func checkArgs(args []string) error {
 			continue
 		}
 
+		tail = append(tail, arg)
+	}
+	return tail
+}
+
+func checkArgs(args []string, rs *Ruleset, fs FS) error {
+	tail := filterPaths(args)
+	recursive := isRecursiveInvocation(args)
+
+	for _, arg := range tail {
 		// any known top level e.g. /usr/bin or /usr/bin/
-		evaluated, match := isTopLevelSystemPath(arg)
+		evaluated, match := isTopLevelSystemPath(arg, rs, fs, recursive)
 		if match {
 			return fmt.Errorf("%w: %s", ErrTopLevelPath, evaluated)
 		}
-
-		tail = append(tail, arg)
 	}
 
 	// If tail is empty (no files specified), skip destructive action check
@@ -167,7 +286,7 @@ func checkArgs(args []string) error {
 	// any potentially destructive path e.g. /usr/bin/*
 	// - add a 🤡 each time you've fallen for that specific one -
 	// 🤡🤡
-	evaluated, match := evaluatePotentiallyDestructiveActions(sanitize(tail))
+	evaluated, match := evaluatePotentiallyDestructiveActions(sanitize(tail), rs, fs, recursive)
 	if match {
 		return fmt.Errorf("%w: %s", ErrTopLevelChildAllContents, evaluated)
 	}
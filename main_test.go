@@ -3,26 +3,24 @@ package main
 import (
 	"errors"
 	"os"
+	"runtime"
 	"strings"
 	"testing"
-)
 
-const dockerTestControlFile = "/tmp/.docker-test-safe-env"
+	"github.com/spf13/afero"
+)
 
-// TestMain ensures we're running in a safe Docker environment.
-func TestMain(m *testing.M) {
-	requireDockerEnv()
-	os.Exit(m.Run())
+// testRuleset returns the built-in ruleset with no user config merged in,
+// so existing tests keep exercising the hard-coded systemPaths behavior.
+func testRuleset() *Ruleset {
+	return defaultRuleset()
 }
 
-// requireDockerEnv checks for the Docker control file and panics if not found.
-// This prevents accidental test execution on the host machine.
-func requireDockerEnv() {
-	if _, err := os.Stat(dockerTestControlFile); os.IsNotExist(err) {
-		panic("FATAL: Tests MUST run in Docker container for safety! " +
-			"The control file " + dockerTestControlFile + " was not found. " +
-			"Use 'just test' to run tests safely in Docker.")
-	}
+// testFS returns an empty in-memory filesystem. Destructive paths like /etc
+// only ever exist here, so exercising them in tests never touches the real
+// disk and no longer requires a Docker sandbox.
+func testFS() FS {
+	return afero.NewMemMapFs()
 }
 
 func TestCheckArgsTopLevelPaths(t *testing.T) {
@@ -105,7 +103,7 @@ func TestCheckArgsTopLevelPaths(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			err := checkArgs(test.args)
+			err := checkArgs(test.args, testRuleset(), testFS())
 
 			if test.expectError {
 				if err == nil {
@@ -147,7 +145,7 @@ func TestCheckArgsFilenamesWithDashes(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			err := checkArgs(test.args)
+			err := checkArgs(test.args, testRuleset(), testFS())
 			if test.expectError && err == nil {
 				t.Error("Expected error but got none")
 			}
@@ -188,7 +186,7 @@ func TestCheckArgsRelativeAndSafePaths(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			err := checkArgs(test.args)
+			err := checkArgs(test.args, testRuleset(), testFS())
 			if test.expectError && err == nil {
 				t.Error("Expected error but got none")
 			}
@@ -219,7 +217,7 @@ func TestCheckArgsEmptyAndFlags(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			err := checkArgs(test.args)
+			err := checkArgs(test.args, testRuleset(), testFS())
 			// Empty args and flags-only should be safe
 			// The actual rm command will handle these cases
 			if test.expectError && err == nil {
@@ -263,7 +261,36 @@ func TestIsGlob(t *testing.T) {
 	}
 }
 
+func TestIsRecursiveInvocation(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected bool
+	}{
+		{"no flags", []string{"/tmp/a"}, false},
+		{"short r flag", []string{"-r", "/tmp/a"}, true},
+		{"short R flag", []string{"-R", "/tmp/a"}, true},
+		{"combined rf flag", []string{"-rf", "/tmp/a"}, true},
+		{"combined fr flag", []string{"-fr", "/tmp/a"}, true},
+		{"long recursive flag", []string{"--recursive", "/tmp/a"}, true},
+		{"force only", []string{"-f", "/tmp/a"}, false},
+		{"r after double dash is a path, not a flag", []string{"--", "-r"}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := isRecursiveInvocation(test.args); result != test.expected {
+				t.Errorf("isRecursiveInvocation(%v) = %v; want %v", test.args, result, test.expected)
+			}
+		})
+	}
+}
+
 func TestIsTopLevelSystemPath(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("exercises the Linux systemPaths set; see syspaths_os_test.go for other platforms")
+	}
+
 	tests := []struct {
 		name        string
 		path        string
@@ -300,7 +327,7 @@ func TestIsTopLevelSystemPath(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			value, match := isTopLevelSystemPath(test.path)
+			value, match := isTopLevelSystemPath(test.path, testRuleset(), testFS(), true)
 			if match != test.expectMatch {
 				t.Errorf("Expected match=%v, got match=%v for path %q", test.expectMatch, match, test.path)
 			}
@@ -353,7 +380,7 @@ func TestEchoGlob(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			result, err := echoGlob(test.pattern)
+			result, err := echoGlob(test.pattern, testFS())
 			if test.expectError {
 				if err == nil {
 					t.Error("Expected error but got none")
@@ -386,7 +413,7 @@ func TestEvaluatePotentiallyDestructiveActions(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			pattern, matched := evaluatePotentiallyDestructiveActions(test.tail)
+			pattern, matched := evaluatePotentiallyDestructiveActions(test.tail, testRuleset(), testFS(), true)
 			if matched != test.shouldMatch {
 				t.Errorf("Expected match=%v, got match=%v for tail %q (pattern: %q)",
 					test.shouldMatch, matched, test.tail, pattern)
@@ -448,7 +475,15 @@ func TestRun(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			// Create temporary files for stdout/stderr
+			// Create temporary files for stdin/stdout/stderr; a regular file
+			// is not a TTY, which is what we want for non-interactive tests.
+			tmpStdin, err := os.CreateTemp("", "stdin")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = os.Remove(tmpStdin.Name()) }() //nolint:errcheck // cleanup in tests
+			defer func() { _ = tmpStdin.Close() }()           //nolint:errcheck // cleanup in tests
+
 			tmpStdout, err := os.CreateTemp("", "stdout")
 			if err != nil {
 				t.Fatal(err)
@@ -471,7 +506,7 @@ func TestRun(t *testing.T) {
 			}
 
 			// Run the function
-			exitCode := run(test.args, tmpStdout, tmpStderr)
+			exitCode := run(test.args, tmpStdin, tmpStdout, tmpStderr, testFS(), &FakeExecutor{})
 
 			// Check exit code
 			if exitCode != test.expectedCode {
@@ -499,6 +534,7 @@ func TestRunWithDifferentDryRunValues(t *testing.T) {
 		name         string
 		dryRunValue  string
 		args         []string
+		executorErr  error
 		expectedCode int
 	}{
 		{
@@ -514,10 +550,11 @@ func TestRunWithDifferentDryRunValues(t *testing.T) {
 			expectedCode: 0,
 		},
 		{
-			name:         "DRY_RUN=false with safe path",
+			name:         "DRY_RUN=false with path the executor fails to remove",
 			dryRunValue:  "false",
 			args:         []string{"/nonexistent/file.txt"},
-			expectedCode: 1, // Will fail because file doesn't exist, but that's ok
+			executorErr:  os.ErrNotExist,
+			expectedCode: 1,
 		},
 		{
 			name:         "DRY_RUN empty with dangerous path",
@@ -529,6 +566,13 @@ func TestRunWithDifferentDryRunValues(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
+			tmpStdin, err := os.CreateTemp("", "stdin")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = os.Remove(tmpStdin.Name()) }() //nolint:errcheck // cleanup in tests
+			defer func() { _ = tmpStdin.Close() }()           //nolint:errcheck // cleanup in tests
+
 			tmpStdout, err := os.CreateTemp("", "stdout")
 			if err != nil {
 				t.Fatal(err)
@@ -545,7 +589,7 @@ func TestRunWithDifferentDryRunValues(t *testing.T) {
 
 			t.Setenv("DRY_RUN", test.dryRunValue)
 
-			exitCode := run(test.args, tmpStdout, tmpStderr)
+			exitCode := run(test.args, tmpStdin, tmpStdout, tmpStderr, testFS(), &FakeExecutor{Err: test.executorErr})
 
 			if exitCode != test.expectedCode {
 				t.Errorf("Expected exit code %d, got %d", test.expectedCode, exitCode)
@@ -554,6 +598,62 @@ func TestRunWithDifferentDryRunValues(t *testing.T) {
 	}
 }
 
+// TestRunRiskyOperationRequiresConfirmation exercises the RiskRisky branch of
+// run(): a non-interactive invocation must hard-fail, and DONTRM_ASSUME_YES=1
+// must bypass the prompt entirely.
+func TestRunRiskyOperationRequiresConfirmation(t *testing.T) {
+	t.Setenv("HOME", "/home/alice")
+	t.Setenv("DRY_RUN", "")
+
+	tmpStdin, err := os.CreateTemp("", "stdin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(tmpStdin.Name()) }() //nolint:errcheck // cleanup in tests
+	defer func() { _ = tmpStdin.Close() }()           //nolint:errcheck // cleanup in tests
+
+	tmpStdout, err := os.CreateTemp("", "stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(tmpStdout.Name()) }() //nolint:errcheck // cleanup in tests
+	defer func() { _ = tmpStdout.Close() }()           //nolint:errcheck // cleanup in tests
+
+	tmpStderr, err := os.CreateTemp("", "stderr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(tmpStderr.Name()) }() //nolint:errcheck // cleanup in tests
+	defer func() { _ = tmpStderr.Close() }()           //nolint:errcheck // cleanup in tests
+
+	args := []string{"/home/alice"}
+
+	t.Run("non-interactive stdin hard-fails", func(t *testing.T) {
+		t.Setenv("DONTRM_ASSUME_YES", "")
+
+		exitCode := run(args, tmpStdin, tmpStdout, tmpStderr, testFS(), &FakeExecutor{})
+		if exitCode != 1 {
+			t.Errorf("expected a risky operation to hard-fail without a TTY, got exit code %d", exitCode)
+		}
+
+		_, _ = tmpStderr.Seek(0, 0) //nolint:errcheck // test helper
+		output := make([]byte, 1000)
+		n, _ := tmpStderr.Read(output) //nolint:errcheck // test helper
+		if !strings.Contains(string(output[:n]), ErrConfirmationRequired.Error()) {
+			t.Errorf("expected stderr to mention %q, got %q", ErrConfirmationRequired, string(output[:n]))
+		}
+	})
+
+	t.Run("DONTRM_ASSUME_YES=1 bypasses the prompt", func(t *testing.T) {
+		t.Setenv("DONTRM_ASSUME_YES", "1")
+
+		exitCode := run(args, tmpStdin, tmpStdout, tmpStderr, testFS(), &FakeExecutor{})
+		if exitCode != 0 {
+			t.Errorf("expected DONTRM_ASSUME_YES=1 to bypass the risky confirmation prompt, got exit code %d", exitCode)
+		}
+	})
+}
+
 // TestDoubleDashStopParsingOptions tests double dash handling.
 func TestDoubleDashStopParsingOptions(t *testing.T) {
 	// Test that -- properly stops option parsing
@@ -581,7 +681,7 @@ func TestDoubleDashStopParsingOptions(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			err := checkArgs(test.args)
+			err := checkArgs(test.args, testRuleset(), testFS())
 			if test.expectError && err == nil {
 				t.Error("Expected error but got none")
 			}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package main
+
+import "path/filepath"
+
+// systemConfigPath is the system-wide default configuration file.
+const systemConfigPath = "/etc/dontrm/config.yaml"
+
+// canonicalizePath cleans path for comparison against protected rules.
+func canonicalizePath(path string) string {
+	return filepath.Clean(path)
+}
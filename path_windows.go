@@ -0,0 +1,32 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// systemConfigPath is the system-wide default configuration file. Windows
+// has no /etc; ProgramData is the conventional location for machine-wide
+// app config, falling back to its default path if the env var is unset.
+var systemConfigPath = func() string {
+	root := os.Getenv("ProgramData")
+	if root == "" {
+		root = `C:\ProgramData`
+	}
+	return filepath.Join(root, "dontrm", "config.yaml")
+}()
+
+// canonicalizePath normalizes a Windows path for case-insensitive,
+// separator-insensitive comparison: forward slashes become backslashes,
+// the drive letter is upper-cased, and the result is lower-cased.
+func canonicalizePath(path string) string {
+	path = strings.ReplaceAll(path, "/", `\`)
+	path = filepath.Clean(path)
+	if len(path) >= 2 && path[1] == ':' {
+		path = strings.ToUpper(path[:1]) + path[1:]
+	}
+	return strings.ToLower(path)
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+)
+
+// Risk classifies how dangerous a delete operation is.
+type Risk int
+
+const (
+	// RiskSafe means the operation can proceed without any prompt.
+	RiskSafe Risk = iota
+	// RiskRisky means the operation should be confirmed before proceeding.
+	RiskRisky
+	// RiskBlocked means the operation is forbidden outright.
+	RiskBlocked
+)
+
+// String renders r for display and audit logging.
+func (r Risk) String() string {
+	switch r {
+	case RiskSafe:
+		return "safe"
+	case RiskRisky:
+		return "risky"
+	case RiskBlocked:
+		return "blocked"
+	default:
+		return "unknown"
+	}
+}
+
+// riskyFileCountThreshold and riskyByteSizeThreshold define when deleting a
+// directory is considered risky purely because of its size, independent of
+// any rule match.
+const (
+	riskyFileCountThreshold = 1000
+	riskyByteSizeThreshold  = 100 * 1024 * 1024 // 100 MiB
+)
+
+// Classification is the result of classifying a delete operation.
+type Classification struct {
+	Risk  Risk
+	Paths []string
+	// FileCount and TotalBytes summarize what a RiskRisky operation would
+	// remove, for display in the confirmation prompt. Left zero for safe and
+	// blocked classifications, where nothing is shown.
+	FileCount  int
+	TotalBytes int64
+	Err        error
+}
+
+// classify runs checkArgs to rule out blocked operations, then evaluates
+// the remaining paths for the risky tier: $HOME itself or its direct
+// children, a rule with require_confirmation, or a directory whose contents
+// exceed the risky size thresholds.
+func classify(args []string, rs *Ruleset, fs FS) Classification {
+	paths := filterPaths(args)
+	recursive := isRecursiveInvocation(args)
+
+	if err := checkArgs(args, rs, fs); err != nil {
+		return Classification{Risk: RiskBlocked, Paths: paths, Err: err}
+	}
+
+	if isRisky(paths, rs, fs, recursive) {
+		files, bytes := summarizePaths(fs, paths)
+		return Classification{Risk: RiskRisky, Paths: paths, FileCount: files, TotalBytes: bytes}
+	}
+
+	return Classification{Risk: RiskSafe, Paths: paths}
+}
+
+// summarizePaths walks paths and totals up the file count and byte size they
+// contain, for display in the risky confirmation prompt.
+func summarizePaths(fs FS, paths []string) (files int, size int64) {
+	for _, path := range paths {
+		info, err := fs.Stat(path)
+		if err != nil {
+			continue
+		}
+		if !info.IsDir() {
+			files++
+			size += info.Size()
+			continue
+		}
+		_ = afero.Walk(fs, path, func(_ string, fi os.FileInfo, err error) error {
+			if err != nil || fi == nil || fi.IsDir() {
+				return nil
+			}
+			files++
+			size += fi.Size()
+			return nil
+		})
+	}
+	return files, size
+}
+
+func isRisky(paths []string, rs *Ruleset, fs FS, recursive bool) bool {
+	home, _ := os.UserHomeDir()
+	homeClean := ""
+	if home != "" {
+		homeClean = canonicalizePath(home)
+	}
+
+	for _, path := range paths {
+		clean := canonicalizePath(path)
+
+		if homeClean != "" && (clean == homeClean || filepath.Dir(clean) == homeClean) {
+			return true
+		}
+		if matchesConfirmationRule(clean, rs, recursive) {
+			return true
+		}
+		if exceedsSizeThresholds(fs, path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesConfirmationRule reports whether path is covered by a user rule
+// tagged require_confirmation: true.
+func matchesConfirmationRule(path string, rs *Ruleset, recursive bool) bool {
+	for _, rule := range rs.ProtectedPaths {
+		if rule.RecursiveOnly && !recursive {
+			continue
+		}
+		if rule.RequireConfirmation && rule.Path == path {
+			return true
+		}
+	}
+	for _, rule := range rs.ProtectedGlobs {
+		if !rule.RequireConfirmation {
+			continue
+		}
+		if ok, _ := filepath.Match(rule.Pattern, path); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// exceedsSizeThresholds reports whether path is a directory whose contents
+// exceed riskyFileCountThreshold files or riskyByteSizeThreshold bytes.
+func exceedsSizeThresholds(fs FS, path string) bool {
+	info, err := fs.Stat(path)
+	if err != nil || !info.IsDir() {
+		return false
+	}
+
+	var files int
+	var size int64
+	_ = afero.Walk(fs, path, func(_ string, fi os.FileInfo, err error) error {
+		if err != nil || fi == nil || fi.IsDir() {
+			return nil
+		}
+		files++
+		size += fi.Size()
+		return nil
+	})
+
+	return files > riskyFileCountThreshold || size > riskyByteSizeThreshold
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestClassifyBlockedTakesPriority(t *testing.T) {
+	c := classify([]string{"-rf", "/etc"}, testRuleset(), testFS())
+	if c.Risk != RiskBlocked {
+		t.Fatalf("expected RiskBlocked, got %v", c.Risk)
+	}
+	if c.Err == nil {
+		t.Error("expected Err to be set for a blocked classification")
+	}
+}
+
+func TestClassifySafePath(t *testing.T) {
+	c := classify([]string{"/var/log/app.log"}, testRuleset(), testFS())
+	if c.Risk != RiskSafe {
+		t.Fatalf("expected RiskSafe, got %v", c.Risk)
+	}
+}
+
+func TestClassifyHomeDirectoryIsRisky(t *testing.T) {
+	t.Setenv("HOME", "/home/alice")
+
+	c := classify([]string{"/home/alice"}, testRuleset(), testFS())
+	if c.Risk != RiskRisky {
+		t.Fatalf("expected RiskRisky for $HOME itself, got %v", c.Risk)
+	}
+
+	c = classify([]string{"/home/alice/Documents"}, testRuleset(), testFS())
+	if c.Risk != RiskRisky {
+		t.Fatalf("expected RiskRisky for a direct child of $HOME, got %v", c.Risk)
+	}
+}
+
+func TestClassifyRequireConfirmationRule(t *testing.T) {
+	rs := defaultRuleset()
+	rs.ProtectedPaths = append(rs.ProtectedPaths, Rule{
+		Path:                "/data/important",
+		RequireConfirmation: true,
+		Source:              "test",
+	})
+
+	c := classify([]string{"/data/important"}, rs, testFS())
+	if c.Risk != RiskRisky {
+		t.Fatalf("expected RiskRisky for a require_confirmation rule match, got %v", c.Risk)
+	}
+}
+
+func TestCheckArgsRecursiveOnlyRuleIgnoresNonRecursiveRemoval(t *testing.T) {
+	rs := defaultRuleset()
+	rs.ProtectedPaths = append(rs.ProtectedPaths, Rule{
+		Path:          "/data/important",
+		RecursiveOnly: true,
+		Source:        "test",
+	})
+
+	if err := checkArgs([]string{"/data/important"}, rs, testFS()); err != nil {
+		t.Errorf("expected a recursive_only rule not to block a non-recursive removal, got %v", err)
+	}
+
+	if err := checkArgs([]string{"-r", "/data/important"}, rs, testFS()); err == nil {
+		t.Error("expected a recursive_only rule to block a recursive removal")
+	}
+}
+
+func TestClassifyRecursiveOnlyRequireConfirmation(t *testing.T) {
+	rs := defaultRuleset()
+	rs.ProtectedPaths = append(rs.ProtectedPaths, Rule{
+		Path:                "/data/important",
+		RecursiveOnly:       true,
+		RequireConfirmation: true,
+		Source:              "test",
+	})
+
+	c := classify([]string{"/data/important"}, rs, testFS())
+	if c.Risk != RiskSafe {
+		t.Fatalf("expected a non-recursive removal to be unaffected by a recursive_only require_confirmation rule, got %v", c.Risk)
+	}
+
+	c = classify([]string{"-r", "/data/important"}, rs, testFS())
+	if c.Risk != RiskRisky {
+		t.Fatalf("expected a recursive removal to trigger the recursive_only require_confirmation rule, got %v", c.Risk)
+	}
+}
+
+func TestClassifyLargeDirectoryIsRisky(t *testing.T) {
+	fs := testFS()
+	if err := fs.MkdirAll("/data/big", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < riskyFileCountThreshold+1; i++ {
+		if err := writeFile(fs, fmt.Sprintf("/data/big/file-%d.txt", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := classify([]string{"/data/big"}, testRuleset(), fs)
+	if c.Risk != RiskRisky {
+		t.Fatalf("expected RiskRisky for a directory over the file-count threshold, got %v", c.Risk)
+	}
+}
+
+func TestIsInteractiveFalseForRegularFile(t *testing.T) {
+	f, err := os.CreateTemp("", "not-a-tty")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(f.Name()) }() //nolint:errcheck // cleanup in tests
+	defer func() { _ = f.Close() }()           //nolint:errcheck // cleanup in tests
+
+	if isInteractive(f) {
+		t.Error("expected a regular file to not be reported as interactive")
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"os/exec"
+)
+
+// rmPath is the system delete command used on Unix-like platforms.
+const rmPath = "/usr/bin/rm"
+
+// executeRemove runs the platform's delete command over args.
+func executeRemove(args []string, stdout, stderr *os.File) error {
+	cmd := exec.Command(rmPath, args...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
@@ -0,0 +1,39 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+	"strings"
+)
+
+// executeRemove deletes each non-flag argument, since Windows has no single
+// rm equivalent that accepts -rf style flags. It mirrors rm's own rule: a
+// directory is only removed when -r/-R/--recursive was passed, via
+// os.RemoveAll; otherwise os.Remove is used, which correctly errors on a
+// non-empty directory instead of silently recursing.
+func executeRemove(args []string, stdout, stderr *os.File) error {
+	recursive := isRecursiveInvocation(args)
+
+	var firstErr error
+	stopParsingOptions := false
+	for _, arg := range args {
+		if arg == "--" {
+			stopParsingOptions = true
+			continue
+		}
+		if !stopParsingOptions && strings.HasPrefix(arg, "-") {
+			continue
+		}
+		var err error
+		if recursive {
+			err = os.RemoveAll(arg)
+		} else {
+			err = os.Remove(arg)
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,27 @@
+//go:build freebsd || openbsd || netbsd || dragonfly
+
+package main
+
+// systemPaths defines the set of top-level system paths protected on BSD
+// variants. It follows the common Unix layout plus the BSD-specific
+// third-party package roots (/usr/local, /usr/pkg).
+var systemPaths = map[string]string{
+	"/":          "/",
+	"/bin":       "/bin",
+	"/boot":      "/boot",
+	"/dev":       "/dev",
+	"/etc":       "/etc",
+	"/home":      "/home",
+	"/lib":       "/lib",
+	"/mnt":       "/mnt",
+	"/opt":       "/opt",
+	"/proc":      "/proc",
+	"/root":      "/root",
+	"/sbin":      "/sbin",
+	"/tmp":       "/tmp",
+	"/usr":       "/usr",
+	"/usr/bin":   "/usr/bin",
+	"/usr/local": "/usr/local",
+	"/usr/pkg":   "/usr/pkg",
+	"/var":       "/var",
+}
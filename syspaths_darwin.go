@@ -0,0 +1,12 @@
+//go:build darwin
+
+package main
+
+// systemPaths defines the set of top-level system paths protected on macOS.
+var systemPaths = map[string]string{
+	"/System":       "/System",
+	"/Applications": "/Applications",
+	"/Library":      "/Library",
+	"/private/etc":  "/private/etc",
+	"/Users":        "/Users",
+}
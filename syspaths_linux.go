@@ -0,0 +1,29 @@
+//go:build linux
+
+package main
+
+// systemPaths defines the set of top-level system paths protected on Linux.
+var systemPaths = map[string]string{
+	"/":         "/",
+	"/bin":      "/bin",
+	"/boot":     "/boot",
+	"/dev":      "/dev",
+	"/etc":      "/etc",
+	"/home":     "/home",
+	"/lib":      "/lib",
+	"/lib64":    "/lib64",
+	"/media":    "/media",
+	"/mnt":      "/mnt",
+	"/opt":      "/opt",
+	"/proc":     "/proc",
+	"/root":     "/root",
+	"/run":      "/run",
+	"/sbin":     "/sbin",
+	"/srv":      "/srv",
+	"/sys":      "/sys",
+	"/tmp":      "/tmp",
+	"/usr":      "/usr",
+	"/usr/bin":  "/usr/bin",
+	"/usr/sbin": "/usr/sbin",
+	"/var":      "/var",
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"runtime"
+	"testing"
+)
+
+// isBSD reports whether the current GOOS is one of the BSD variants that
+// share syspaths_bsd.go.
+func isBSD() bool {
+	switch runtime.GOOS {
+	case "freebsd", "openbsd", "netbsd", "dragonfly":
+		return true
+	default:
+		return false
+	}
+}
+
+// TestSystemPathsForGOOS checks that the systemPaths selected by the build
+// tags for the host platform contain the paths that platform is supposed to
+// protect. Each run checks only the host GOOS's variant; a CI runner on each
+// platform covers that platform's set without needing Docker.
+func TestSystemPathsForGOOS(t *testing.T) {
+	var want []string
+	switch {
+	case runtime.GOOS == "linux":
+		want = []string{"/", "/bin", "/etc", "/usr", "/usr/bin", "/var"}
+	case runtime.GOOS == "darwin":
+		want = []string{"/System", "/Applications", "/Library", "/private/etc", "/Users"}
+	case runtime.GOOS == "windows":
+		want = []string{`C:\Windows`, `C:\Program Files`, `C:\Program Files (x86)`, `C:\Users`}
+	case isBSD():
+		want = []string{"/", "/bin", "/etc", "/usr", "/usr/local", "/usr/pkg"}
+	default:
+		t.Skipf("no systemPaths expectations recorded for GOOS=%s", runtime.GOOS)
+	}
+
+	for _, path := range want {
+		if _, ok := systemPaths[path]; !ok {
+			t.Errorf("expected systemPaths to protect %q on %s", path, runtime.GOOS)
+		}
+	}
+}
+
+// TestCanonicalizePath exercises the OS-specific path normalization used
+// before comparing an argument against the protected rule set.
+func TestCanonicalizePath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		tests := []struct {
+			name  string
+			input string
+			want  string
+		}{
+			{"forward slashes", "c:/windows", `c:\windows`},
+			{"mixed case drive", "c:\\Windows", `c:\windows`},
+			{"trailing slash", `C:\Users\`, `c:\users`},
+		}
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				if got := canonicalizePath(test.input); got != test.want {
+					t.Errorf("canonicalizePath(%q) = %q; want %q", test.input, got, test.want)
+				}
+			})
+		}
+		return
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"trailing slash", "/etc/", "/etc"},
+		{"double slash", "//tmp", "/tmp"},
+		{"dot segment", "/usr/./bin", "/usr/bin"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := canonicalizePath(test.input); got != test.want {
+				t.Errorf("canonicalizePath(%q) = %q; want %q", test.input, got, test.want)
+			}
+		})
+	}
+}
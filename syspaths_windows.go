@@ -0,0 +1,13 @@
+//go:build windows
+
+package main
+
+// systemPaths defines the set of top-level system paths protected on
+// Windows. Comparisons against these go through canonicalizePath, so the
+// casing and separator style used here doesn't matter.
+var systemPaths = map[string]string{
+	`C:\Windows`:             `C:\Windows`,
+	`C:\Program Files`:       `C:\Program Files`,
+	`C:\Program Files (x86)`: `C:\Program Files (x86)`,
+	`C:\Users`:               `C:\Users`,
+}
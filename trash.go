@@ -0,0 +1,313 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// trashEnabled reports whether targets should be moved to the trash instead
+// of removed outright: either the caller passed --trash, or DONTRM_TRASH=1
+// is set in the environment.
+func trashEnabled(args []string) bool {
+	for _, arg := range args {
+		if arg == "--trash" {
+			return true
+		}
+	}
+	return os.Getenv("DONTRM_TRASH") == "1"
+}
+
+// stripTrashFlag removes --trash from args so it never reaches rm.
+func stripTrashFlag(args []string) []string {
+	out := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg == "--trash" {
+			continue
+		}
+		out = append(out, arg)
+	}
+	return out
+}
+
+// trashRoot resolves the FreeDesktop-style trash directory: $XDG_DATA_HOME/Trash
+// when set, falling back to ~/.local/share/Trash on Linux and ~/.Trash on macOS.
+func trashRoot() (string, error) {
+	if xdg := os.Getenv("XDG_DATA_HOME"); xdg != "" {
+		return filepath.Join(xdg, "Trash"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	if runtime.GOOS == "darwin" {
+		return filepath.Join(home, ".Trash"), nil
+	}
+	return filepath.Join(home, ".local", "share", "Trash"), nil
+}
+
+func trashFilesDir(root string) string { return filepath.Join(root, "files") }
+func trashInfoDir(root string) string  { return filepath.Join(root, "info") }
+
+// moveToTrash moves path into the trash directory instead of deleting it,
+// writing a .trashinfo sidecar recording where it came from.
+func moveToTrash(fs FS, path string) error {
+	root, err := trashRoot()
+	if err != nil {
+		return err
+	}
+
+	filesDir := trashFilesDir(root)
+	infoDir := trashInfoDir(root)
+	if err := fs.MkdirAll(filesDir, 0o700); err != nil {
+		return err
+	}
+	if err := fs.MkdirAll(infoDir, 0o700); err != nil {
+		return err
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	name := uniqueTrashName(fs, filesDir, filepath.Base(path))
+	dest := filepath.Join(filesDir, name)
+
+	if err := renameOrCopy(fs, path, dest); err != nil {
+		return err
+	}
+
+	return writeTrashInfo(fs, filepath.Join(infoDir, name+".trashinfo"), abs, time.Now())
+}
+
+// uniqueTrashName returns base, or base with a numeric suffix inserted
+// before its extension, such that it doesn't already exist in filesDir.
+func uniqueTrashName(fs FS, filesDir, base string) string {
+	candidate := base
+	ext := filepath.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+	for i := 2; ; i++ {
+		exists, err := afero.Exists(fs, filepath.Join(filesDir, candidate))
+		if err != nil || !exists {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s.%d%s", stem, i, ext)
+	}
+}
+
+// renameOrCopy moves src to dst, falling back to a copy+remove when they're
+// on different devices (mirrors the moby/fileutils CopyFile pattern for the
+// EXDEV case os.Rename can't handle atomically).
+func renameOrCopy(fs FS, src, dst string) error {
+	err := fs.Rename(src, dst)
+	if err == nil {
+		return nil
+	}
+	if !isCrossDeviceError(err) {
+		return err
+	}
+
+	if err := copyFile(fs, src, dst); err != nil {
+		return err
+	}
+	return fs.RemoveAll(src)
+}
+
+func copyFile(fs FS, src, dst string) error {
+	in, err := fs.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }() //nolint:errcheck // best-effort close after copy
+
+	out, err := fs.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = out.Close() }() //nolint:errcheck // close error surfaces via the explicit Close below
+
+	if _, err := io.Copy(out, in); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// writeTrashInfo writes a FreeDesktop-compliant .trashinfo sidecar.
+func writeTrashInfo(fs FS, infoPath, originalPath string, deletedAt time.Time) error {
+	content := fmt.Sprintf("[Trash Info]\nPath=%s\nDeletionDate=%s\n", originalPath, deletedAt.Format(time.RFC3339))
+	return afero.WriteFile(fs, infoPath, []byte(content), 0o644)
+}
+
+// trashEntry is a parsed .trashinfo sidecar.
+type trashEntry struct {
+	Name         string
+	OriginalPath string
+	DeletionDate time.Time
+}
+
+// parseTrashInfo reads the Path= and DeletionDate= fields out of a
+// .trashinfo file's contents.
+func parseTrashInfo(data []byte) (originalPath string, deletionDate time.Time, err error) {
+	for _, line := range strings.Split(string(data), "\n") {
+		switch {
+		case strings.HasPrefix(line, "Path="):
+			originalPath = strings.TrimPrefix(line, "Path=")
+		case strings.HasPrefix(line, "DeletionDate="):
+			raw := strings.TrimPrefix(line, "DeletionDate=")
+			deletionDate, err = time.Parse(time.RFC3339, raw)
+			if err != nil {
+				return "", time.Time{}, fmt.Errorf("parsing DeletionDate: %w", err)
+			}
+		}
+	}
+	if originalPath == "" {
+		return "", time.Time{}, fmt.Errorf("missing Path= field")
+	}
+	return originalPath, deletionDate, nil
+}
+
+// listTrash returns every trashed entry, sorted by name.
+func listTrash(fs FS) ([]trashEntry, error) {
+	root, err := trashRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	infoDir := trashInfoDir(root)
+	infos, err := afero.ReadDir(fs, infoDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	entries := make([]trashEntry, 0, len(infos))
+	for _, info := range infos {
+		if !strings.HasSuffix(info.Name(), ".trashinfo") {
+			continue
+		}
+		data, err := afero.ReadFile(fs, filepath.Join(infoDir, info.Name()))
+		if err != nil {
+			return nil, err
+		}
+		originalPath, deletionDate, err := parseTrashInfo(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", info.Name(), err)
+		}
+		entries = append(entries, trashEntry{
+			Name:         strings.TrimSuffix(info.Name(), ".trashinfo"),
+			OriginalPath: originalPath,
+			DeletionDate: deletionDate,
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries, nil
+}
+
+// restoreFromTrash moves name back to the original path recorded in its
+// .trashinfo sidecar, then removes the sidecar.
+func restoreFromTrash(fs FS, name string) error {
+	root, err := trashRoot()
+	if err != nil {
+		return err
+	}
+
+	infoPath := filepath.Join(trashInfoDir(root), name+".trashinfo")
+	data, err := afero.ReadFile(fs, infoPath)
+	if err != nil {
+		return err
+	}
+
+	originalPath, _, err := parseTrashInfo(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	if err := renameOrCopy(fs, filepath.Join(trashFilesDir(root), name), originalPath); err != nil {
+		return err
+	}
+
+	return fs.Remove(infoPath)
+}
+
+// emptyTrash permanently deletes every trashed file and its sidecar.
+func emptyTrash(fs FS) error {
+	root, err := trashRoot()
+	if err != nil {
+		return err
+	}
+
+	if err := fs.RemoveAll(trashFilesDir(root)); err != nil {
+		return err
+	}
+	return fs.RemoveAll(trashInfoDir(root))
+}
+
+// runTrashCommand implements the `dontrm trash` subcommands. argv is the
+// full invocation (e.g. ["trash", "empty"]), recorded verbatim in the audit
+// entries that restore/empty produce, since both change the filesystem
+// without going through checkArgs: restore moves a file back to its original
+// path, and empty deletes trashed files permanently.
+func runTrashCommand(argv []string, fs FS, stdout, stderr *os.File) int {
+	args := argv[1:]
+	if len(args) == 0 {
+		_, _ = fmt.Fprintln(stderr, "usage: dontrm trash [list|restore <name>|empty]")
+		return 1
+	}
+
+	switch args[0] {
+	case "list":
+		entries, err := listTrash(fs)
+		if err != nil {
+			_, _ = fmt.Fprintln(stderr, err.Error())
+			return 1
+		}
+		for _, e := range entries {
+			fmt.Fprintf(stdout, "%-30s %-40s %s\n", e.Name, e.OriginalPath, e.DeletionDate.Format(time.RFC3339))
+		}
+		return 0
+	case "restore":
+		if len(args) < 2 {
+			_, _ = fmt.Fprintln(stderr, "usage: dontrm trash restore <name>")
+			return 1
+		}
+		err := restoreFromTrash(fs, args[1])
+		exitCode := 0
+		if err != nil {
+			_, _ = fmt.Fprintln(stderr, err.Error())
+			exitCode = 1
+		}
+		recordAudit(fs, argv, Classification{Risk: RiskSafe, Paths: []string{args[1]}}, exitCode, nil, err == nil)
+		return exitCode
+	case "empty":
+		trashed, _ := listTrash(fs)
+		paths := make([]string, 0, len(trashed))
+		for _, e := range trashed {
+			paths = append(paths, e.OriginalPath)
+		}
+
+		err := emptyTrash(fs)
+		exitCode := 0
+		if err != nil {
+			_, _ = fmt.Fprintln(stderr, err.Error())
+			exitCode = 1
+		}
+		recordAudit(fs, argv, Classification{Risk: RiskSafe, Paths: paths}, exitCode, nil, err == nil)
+		return exitCode
+	default:
+		_, _ = fmt.Fprintf(stderr, "unknown trash subcommand: %s\n", args[0])
+		return 1
+	}
+}
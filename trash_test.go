@@ -0,0 +1,245 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestTrashRootRespectsXDGDataHome(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/xdg")
+
+	root, err := trashRoot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root != "/xdg/Trash" {
+		t.Errorf("expected /xdg/Trash, got %q", root)
+	}
+}
+
+func TestTrashRootFallsBackPerOS(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "")
+
+	root, err := trashRoot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := filepath.Join(home, ".local", "share", "Trash")
+	if runtime.GOOS == "darwin" {
+		want = filepath.Join(home, ".Trash")
+	}
+	if root != want {
+		t.Errorf("expected %q on %s, got %q", want, runtime.GOOS, root)
+	}
+}
+
+func TestUniqueTrashNameAvoidsCollisions(t *testing.T) {
+	fs := testFS()
+	filesDir := "/trash/files"
+	if err := fs.MkdirAll(filesDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFile(fs, filesDir+"/note.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	name := uniqueTrashName(fs, filesDir, "note.txt")
+	if name != "note.2.txt" {
+		t.Errorf("expected note.2.txt, got %q", name)
+	}
+}
+
+func TestParseTrashInfo(t *testing.T) {
+	data := []byte("[Trash Info]\nPath=/home/user/file.txt\nDeletionDate=2026-07-25T12:00:00Z\n")
+
+	path, date, err := parseTrashInfo(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if path != "/home/user/file.txt" {
+		t.Errorf("expected path /home/user/file.txt, got %q", path)
+	}
+	if date.Format(time.RFC3339) != "2026-07-25T12:00:00Z" {
+		t.Errorf("expected parsed date, got %v", date)
+	}
+}
+
+func TestParseTrashInfoMissingPath(t *testing.T) {
+	if _, _, err := parseTrashInfo([]byte("[Trash Info]\n")); err == nil {
+		t.Error("expected error for missing Path= field")
+	}
+}
+
+func TestMoveListRestoreRoundTrip(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/xdg")
+	fs := testFS()
+
+	if err := fs.MkdirAll("/home/user", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFile(fs, "/home/user/doc.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := moveToTrash(fs, "/home/user/doc.txt"); err != nil {
+		t.Fatalf("moveToTrash failed: %v", err)
+	}
+
+	if exists, _ := fs.Open("/home/user/doc.txt"); exists != nil {
+		t.Error("expected original file to be gone after moving to trash")
+	}
+
+	entries, err := listTrash(fs)
+	if err != nil {
+		t.Fatalf("listTrash failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].OriginalPath != "/home/user/doc.txt" {
+		t.Fatalf("expected one trash entry for /home/user/doc.txt, got %+v", entries)
+	}
+
+	if err := restoreFromTrash(fs, entries[0].Name); err != nil {
+		t.Fatalf("restoreFromTrash failed: %v", err)
+	}
+
+	if _, err := fs.Open("/home/user/doc.txt"); err != nil {
+		t.Errorf("expected restored file to exist, got error: %v", err)
+	}
+
+	entries, err = listTrash(fs)
+	if err != nil {
+		t.Fatalf("listTrash failed: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected trash to be empty after restore, got %+v", entries)
+	}
+}
+
+func TestEmptyTrash(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/xdg")
+	fs := testFS()
+
+	if err := fs.MkdirAll("/tmp", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFile(fs, "/tmp/gone.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := moveToTrash(fs, "/tmp/gone.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := emptyTrash(fs); err != nil {
+		t.Fatalf("emptyTrash failed: %v", err)
+	}
+
+	entries, err := listTrash(fs)
+	if err != nil {
+		t.Fatalf("listTrash failed: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no entries after empty, got %+v", entries)
+	}
+}
+
+func TestTrashEnabled(t *testing.T) {
+	t.Setenv("DONTRM_TRASH", "")
+
+	if !trashEnabled([]string{"--trash", "/tmp/a"}) {
+		t.Error("expected --trash to enable trashing")
+	}
+	if trashEnabled([]string{"/tmp/a"}) {
+		t.Error("expected trashing to be disabled by default")
+	}
+
+	t.Setenv("DONTRM_TRASH", "1")
+	if !trashEnabled([]string{"/tmp/a"}) {
+		t.Error("expected DONTRM_TRASH=1 to enable trashing")
+	}
+}
+
+func TestStripTrashFlag(t *testing.T) {
+	got := stripTrashFlag([]string{"-rf", "--trash", "/tmp/a"})
+	want := []string{"-rf", "/tmp/a"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+// TestRunTrashCommandDispatch exercises the trash subcommand dispatch
+// (list/restore/empty) end to end, rather than just the listTrash /
+// restoreFromTrash / emptyTrash helpers underneath it.
+func TestRunTrashCommandDispatch(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/xdg")
+	t.Setenv("XDG_STATE_HOME", "/xdg-state")
+	fs := testFS()
+
+	if err := fs.MkdirAll("/home/user", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFile(fs, "/home/user/doc.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := moveToTrash(fs, "/home/user/doc.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpOut, err := os.CreateTemp("", "trash-out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(tmpOut.Name()) }() //nolint:errcheck // cleanup in tests
+	defer func() { _ = tmpOut.Close() }()           //nolint:errcheck // cleanup in tests
+
+	if code := runTrashCommand([]string{"trash", "list"}, fs, tmpOut, tmpOut); code != 0 {
+		t.Fatalf("expected exit 0 for trash list, got %d", code)
+	}
+
+	entries, err := listTrash(fs)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected one trashed entry, got %+v (err %v)", entries, err)
+	}
+
+	if code := runTrashCommand([]string{"trash", "restore", entries[0].Name}, fs, tmpOut, tmpOut); code != 0 {
+		t.Fatalf("expected exit 0 for trash restore, got %d", code)
+	}
+	if _, err := fs.Open("/home/user/doc.txt"); err != nil {
+		t.Fatalf("expected restored file to exist, got error: %v", err)
+	}
+
+	if err := moveToTrash(fs, "/home/user/doc.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if code := runTrashCommand([]string{"trash", "empty"}, fs, tmpOut, tmpOut); code != 0 {
+		t.Fatalf("expected exit 0 for trash empty, got %d", code)
+	}
+	if entries, err := listTrash(fs); err != nil || len(entries) != 0 {
+		t.Fatalf("expected empty trash after emptying, got %+v (err %v)", entries, err)
+	}
+
+	auditLog, err := auditEntries(fs)
+	if err != nil {
+		t.Fatalf("unexpected error reading audit log: %v", err)
+	}
+	if len(auditLog) != 2 {
+		t.Fatalf("expected restore and empty to each record an audit entry, got %d", len(auditLog))
+	}
+	for _, e := range auditLog {
+		if !e.Executed {
+			t.Errorf("expected trash restore/empty to be recorded as executed, got %+v", e)
+		}
+	}
+}
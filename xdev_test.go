@@ -0,0 +1,79 @@
+package main
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+// crossDeviceFS wraps an FS and makes every Rename fail as if src and dst
+// were on different devices (EXDEV), forcing callers onto the copy+unlink
+// fallback renameOrCopy implements.
+type crossDeviceFS struct {
+	FS
+}
+
+func (crossDeviceFS) Rename(oldname, newname string) error {
+	return &pathErrorEXDEV{oldname, newname}
+}
+
+// pathErrorEXDEV mimics the shape of a real cross-device rename failure
+// closely enough for errors.Is(err, syscall.EXDEV) to succeed, which is what
+// isCrossDeviceError checks for on Unix.
+type pathErrorEXDEV struct {
+	oldname, newname string
+}
+
+func (e *pathErrorEXDEV) Error() string {
+	return "rename " + e.oldname + " " + e.newname + ": invalid cross-device link"
+}
+
+func (e *pathErrorEXDEV) Unwrap() error { return syscall.EXDEV }
+
+func TestRenameOrCopyFallsBackOnCrossDeviceRename(t *testing.T) {
+	fs := crossDeviceFS{testFS()}
+
+	if err := writeFile(fs, "/src/file.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := renameOrCopy(fs, "/src/file.txt", "/dst/file.txt"); err != nil {
+		t.Fatalf("renameOrCopy failed: %v", err)
+	}
+
+	if exists, _ := afero.Exists(fs, "/src/file.txt"); exists {
+		t.Error("expected source to be removed after the copy+unlink fallback")
+	}
+	if exists, _ := afero.Exists(fs, "/dst/file.txt"); !exists {
+		t.Error("expected destination to exist after the copy+unlink fallback")
+	}
+}
+
+func TestMoveToTrashFallsBackOnCrossDeviceRename(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/xdg")
+	fs := crossDeviceFS{testFS()}
+
+	if err := fs.MkdirAll("/home/user", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFile(fs, "/home/user/doc.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := moveToTrash(fs, "/home/user/doc.txt"); err != nil {
+		t.Fatalf("moveToTrash failed: %v", err)
+	}
+
+	if exists, _ := afero.Exists(fs, "/home/user/doc.txt"); exists {
+		t.Error("expected original file to be gone after moving to trash via the copy fallback")
+	}
+
+	entries, err := listTrash(fs)
+	if err != nil {
+		t.Fatalf("listTrash failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].OriginalPath != "/home/user/doc.txt" {
+		t.Fatalf("expected one trash entry for /home/user/doc.txt, got %+v", entries)
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package main
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isCrossDeviceError reports whether err indicates the source and
+// destination of a rename are on different filesystems (EXDEV), meaning the
+// caller must fall back to a copy+remove.
+func isCrossDeviceError(err error) bool {
+	return errors.Is(err, syscall.EXDEV)
+}
@@ -0,0 +1,11 @@
+//go:build windows
+
+package main
+
+// isCrossDeviceError reports whether a failed rename should fall back to a
+// copy+remove. Windows surfaces a cross-volume rename as a generic
+// *LinkError without a portable errno, so any rename failure is treated as
+// recoverable by the copy+remove fallback.
+func isCrossDeviceError(err error) bool {
+	return err != nil
+}